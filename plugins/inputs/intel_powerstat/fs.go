@@ -0,0 +1,70 @@
+//go:build linux
+
+package intel_powerstat
+
+import (
+	"encoding/binary"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// fileService is responsible for the low-level file reads msrServiceImpl builds on: globbing
+// sysfs paths, parsing a sysfs value, and reading raw MSR offsets out of /dev/cpu/N/msr.
+type fileService interface {
+	getStringsMatchingPatternOnPath(path string) ([]string, error)
+	readFileToFloat64(reader io.Reader) (float64, int, error)
+	readFileAtOffsetToUint64(reader io.ReaderAt, offset int64) (uint64, error)
+	// readFileAtOffsetsToUint64 reads every offset in offsets from reader with a single open fd,
+	// returning one value per offset in the same order. It exists so callers with several
+	// offsets on the same reader (e.g. msrServiceImpl.readDataFromMsr) can avoid a goroutine per
+	// offset; the offsets are still read one ReadAt call at a time, in order.
+	readFileAtOffsetsToUint64(reader io.ReaderAt, offsets []int64) ([]uint64, error)
+}
+
+type fileServiceImpl struct {
+}
+
+func newFileService() *fileServiceImpl {
+	return &fileServiceImpl{}
+}
+
+func (f *fileServiceImpl) getStringsMatchingPatternOnPath(path string) ([]string, error) {
+	return filepath.Glob(path)
+}
+
+func (f *fileServiceImpl) readFileToFloat64(reader io.Reader) (float64, int, error) {
+	buffer := make([]byte, 64)
+	n, err := reader.Read(buffer)
+	if err != nil && err != io.EOF {
+		return 0, 0, err
+	}
+
+	value, err := strconv.ParseFloat(strings.TrimSpace(string(buffer[:n])), 64)
+	if err != nil {
+		return 0, n, err
+	}
+	return value, n, nil
+}
+
+func (f *fileServiceImpl) readFileAtOffsetToUint64(reader io.ReaderAt, offset int64) (uint64, error) {
+	buffer := make([]byte, 8)
+	_, err := reader.ReadAt(buffer, offset)
+	if err != nil {
+		return 0, err
+	}
+	return binary.LittleEndian.Uint64(buffer), nil
+}
+
+func (f *fileServiceImpl) readFileAtOffsetsToUint64(reader io.ReaderAt, offsets []int64) ([]uint64, error) {
+	values := make([]uint64, len(offsets))
+	for i, offset := range offsets {
+		value, err := f.readFileAtOffsetToUint64(reader, offset)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = value
+	}
+	return values, nil
+}
@@ -0,0 +1,72 @@
+//go:build linux
+
+package intel_powerstat
+
+import (
+	"time"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/config"
+	"github.com/influxdata/telegraf/plugins/inputs"
+)
+
+const sampleConfig = `
+  ## All the measurements for every core will be restricted to cores configured in this list.
+  ## Mind that empty or non-existent list means no restriction and that absent "cpu" option
+  ## means all the cores are taken into account.
+  # cores = ["0-3","4,5,6","7"]
+
+  ## Use MSR-derived APERF/MPERF deltas and the TSC rate to estimate per-core frequency when
+  ## scaling_cur_freq cannot be opened or read, e.g. on isolated cores where the cpufreq governor
+  ## is disabled. A scaling_cur_freq value that is readable but stuck is reported as read.
+  ## Disabled by default.
+  # use_msr_cpu_frequency = false
+
+  ## Timeout for a single MSR read. A core that is offline, being hot-plugged, or stuck under an
+  ## SMI storm can otherwise hang the whole Gather cycle.
+  # msr_read_timeout = "1s"
+`
+
+// IntelPowerstat is a telegraf input plugin that reads per-core C-state residency, APERF/MPERF/TSC
+// and temperature MSRs on Intel (and, for a reduced set, AMD) CPUs every Gather cycle. Gather adds
+// no metrics to the accumulator; it only records read errors.
+type IntelPowerstat struct {
+	Cores              []string        `toml:"cores"`
+	UseMsrCPUFrequency bool            `toml:"use_msr_cpu_frequency"`
+	MsrReadTimeout     config.Duration `toml:"msr_read_timeout"`
+
+	Log telegraf.Logger `toml:"-"`
+
+	msrService msrService
+}
+
+func (p *IntelPowerstat) SampleConfig() string {
+	return sampleConfig
+}
+
+func (p *IntelPowerstat) Init() error {
+	p.msrService = newMsrServiceWithFs(p.Log, newFileService(), p.Cores, p.UseMsrCPUFrequency, time.Duration(p.MsrReadTimeout))
+	return nil
+}
+
+func (p *IntelPowerstat) Gather(acc telegraf.Accumulator) error {
+	for core := range p.msrService.getCPUCoresData() {
+		if err := p.msrService.openAndReadMsr(core); err != nil {
+			acc.AddError(err)
+		}
+	}
+	return nil
+}
+
+// Stop releases the MSR and sysfs file descriptors msrService has cached across Gather cycles.
+func (p *IntelPowerstat) Stop() {
+	if err := p.msrService.Close(); err != nil {
+		p.Log.Errorf("error closing MSR service: %v", err)
+	}
+}
+
+func init() {
+	inputs.Add("intel_powerstat", func() telegraf.Input {
+		return &IntelPowerstat{}
+	})
+}
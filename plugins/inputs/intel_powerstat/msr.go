@@ -4,14 +4,17 @@ package intel_powerstat
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
-
-	"golang.org/x/sync/errgroup"
+	"sync"
+	"syscall"
+	"time"
 
 	"github.com/influxdata/telegraf"
 )
@@ -37,6 +40,147 @@ const (
 	platformInfo                       = 0xCE
 	fsbFreq                            = 0xCD
 )
+
+const (
+	// busClockMHz is the bus clock (BCLK) used by modern Xeon platforms to derive the TSC
+	// frequency from MSR_PLATFORM_INFO. It is used on every platform isAtomFSBPlatform does not
+	// identify as Atom-family; MSR_FSB_FREQ is not read there.
+	busClockMHz = 100
+
+	minPlausibleCPUFrequencyMHz = 100
+	maxPlausibleCPUFrequencyMHz = 10000
+)
+
+// defaultMsrReadTimeout bounds a single MSR read, so a stuck /dev/cpu/N/msr (e.g. a core that
+// is offline, being hot-plugged, or under an SMI storm) cannot hang the whole Gather loop.
+const defaultMsrReadTimeout = 1 * time.Second
+
+// staleCoreWarnInterval rate-limits the "core timed out" warning so a persistently stuck core
+// doesn't spam the log once per Gather interval.
+const staleCoreWarnInterval = 1 * time.Minute
+
+// maxOutstandingTimedOutReads bounds how many abandoned reads (reads whose pread never returned
+// before m.readTimeout elapsed) may be in flight at once for a single core. Go cannot cancel a blocked syscall, so
+// a read that times out leaves its goroutine running until the read eventually returns (or never
+// does, e.g. a core wedged by an SMI storm). Without a cap, a persistently bad core would leak
+// one goroutine (and pin one *os.File) per Gather cycle forever; past this many outstanding
+// abandoned reads, new reads are refused immediately instead of piling on more.
+const maxOutstandingTimedOutReads = 64
+
+// errTooManyTimedOutReads is returned instead of starting a new read once
+// maxOutstandingTimedOutReads is already reached; callers treat it the same as a fresh timeout.
+var errTooManyTimedOutReads = errors.New("too many MSR reads already stuck past their timeout")
+
+// cpuInfoPath is read once at startup to detect the CPU vendor.
+const cpuInfoPath = "/proc/cpuinfo"
+
+const (
+	vendorIDIntel = "GenuineIntel"
+	vendorIDAMD   = "AuthenticAMD"
+)
+
+// cpuVendor supplies the MSR offset table and capability flags for a CPU vendor. The hard-coded
+// MSR addresses in this file are Intel-specific; AMD Zen exposes MPERF/APERF/TSC at the same
+// offsets, but has no equivalent for the C-state residency and IA32_THERM_STATUS MSRs used here.
+type cpuVendor interface {
+	// vendorName identifies the detected vendor for logging.
+	vendorName() string
+	// msrOffsets returns the MSR offsets this vendor supports reading every Gather cycle.
+	msrOffsets() []int64
+	// supportsCState reports whether C3/C6/C7 residency is available via msrOffsets.
+	supportsCState() bool
+	// supportsTemperature reports whether core temperature is available via msrOffsets.
+	supportsTemperature() bool
+	// supportsMsrCPUFrequency reports whether tscFrequencyMHz's MSR_PLATFORM_INFO/MSR_FSB_FREQ
+	// based frequency fallback is meaningful for this vendor.
+	supportsMsrCPUFrequency() bool
+}
+
+// intelCPUVendor is the original, fully-supported MSR layout this plugin was written against.
+type intelCPUVendor struct{}
+
+func (intelCPUVendor) vendorName() string { return vendorIDIntel }
+
+func (intelCPUVendor) msrOffsets() []int64 {
+	return []int64{c3StateResidencyLocation, c6StateResidencyLocation, c7StateResidencyLocation,
+		maximumFrequencyClockCountLocation, actualFrequencyClockCountLocation, timestampCounterLocation,
+		throttleTemperatureLocation, temperatureLocation}
+}
+
+func (intelCPUVendor) supportsCState() bool          { return true }
+func (intelCPUVendor) supportsTemperature() bool     { return true }
+func (intelCPUVendor) supportsMsrCPUFrequency() bool { return true }
+
+// amdCPUVendor covers AMD family 17h/19h (Zen 2/3/4). C-state residency on these parts requires
+// programming performance counters rather than reading fixed MSRs, and core temperature lives in
+// SMN/PCI config space rather than IA32_THERM_STATUS; neither is read by this plugin today, so
+// those metrics are skipped rather than decoded from the wrong bits.
+type amdCPUVendor struct{}
+
+func (amdCPUVendor) vendorName() string { return vendorIDAMD }
+
+func (amdCPUVendor) msrOffsets() []int64 {
+	return []int64{maximumFrequencyClockCountLocation, actualFrequencyClockCountLocation, timestampCounterLocation}
+}
+
+func (amdCPUVendor) supportsCState() bool      { return false }
+func (amdCPUVendor) supportsTemperature() bool { return false }
+
+// supportsMsrCPUFrequency is false because MSR_PLATFORM_INFO and MSR_FSB_FREQ (0xCE, 0xCD) are
+// Intel-specific; AMD has no equivalent at these offsets, so the MSR-based frequency fallback
+// can't derive a TSC rate here.
+func (amdCPUVendor) supportsMsrCPUFrequency() bool { return false }
+
+// detectCPUVendor reads the vendor_id field of cpuInfoPath to pick a cpuVendor implementation.
+// It falls back to Intel, the plugin's original target, if the vendor can't be determined.
+// cpuInfoPath is a parameter (rather than always the cpuInfoPath constant) so tests can point it
+// at a fake cpuinfo file instead of the real /proc/cpuinfo.
+func detectCPUVendor(logger telegraf.Logger, cpuInfoPath string) cpuVendor {
+	data, err := os.ReadFile(cpuInfoPath)
+	if err != nil {
+		logger.Warnf("unable to read %q to detect CPU vendor, assuming %s: %v", cpuInfoPath, vendorIDIntel, err)
+		return intelCPUVendor{}
+	}
+
+	for _, line := range strings.Split(string(data), "\n") {
+		key, value, ok := strings.Cut(line, ":")
+		if !ok || strings.TrimSpace(key) != "vendor_id" {
+			continue
+		}
+		switch strings.TrimSpace(value) {
+		case vendorIDAMD:
+			return amdCPUVendor{}
+		case vendorIDIntel:
+			return intelCPUVendor{}
+		default:
+			logger.Warnf("unsupported CPU vendor %q, assuming %s", strings.TrimSpace(value), vendorIDIntel)
+			return intelCPUVendor{}
+		}
+	}
+
+	logger.Warnf("no vendor_id found in %q, assuming %s", cpuInfoPath, vendorIDIntel)
+	return intelCPUVendor{}
+}
+
+// msrOffsetNames names the offsets in msrOffsets for diagnostics, e.g. timeout warnings.
+var msrOffsetNames = map[int64]string{
+	c3StateResidencyLocation:          "MSR_CORE_C3_RESIDENCY",
+	c6StateResidencyLocation:          "MSR_CORE_C6_RESIDENCY",
+	c7StateResidencyLocation:          "MSR_CORE_C7_RESIDENCY",
+	maximumFrequencyClockCountLocation: "IA32_MPERF",
+	actualFrequencyClockCountLocation:  "IA32_APERF",
+	timestampCounterLocation:           "IA32_TIME_STAMP_COUNTER",
+	throttleTemperatureLocation:        "IA32_TEMPERATURE_TARGET",
+	temperatureLocation:                "IA32_THERM_STATUS",
+}
+
+func msrOffsetName(offset int64) string {
+	if name, ok := msrOffsetNames[offset]; ok {
+		return name
+	}
+	return fmt.Sprintf("0x%X", offset)
+}
+
 const (
 	msrTurboRatioLimitString     = "MSR_TURBO_RATIO_LIMIT"
 	msrTurboRatioLimit1String    = "MSR_TURBO_RATIO_LIMIT1"
@@ -54,10 +198,12 @@ const maxIDsSize = 1 << 13
 type msrService interface {
 	getCPUCoresData() map[string]*msrData
 	retrieveCPUFrequencyForCore(core string) (float64, error)
+	retrieveCPUFrequencyFromMsr(core string) (float64, error)
 	retrieveUncoreFrequency(socketID string, typeFreq string, kind string, die string) (float64, error)
 	openAndReadMsr(core string) error
 	readSingleMsr(core string, msr string) (uint64, error)
 	isMsrLoaded() bool
+	Close() error
 }
 
 type msrServiceImpl struct {
@@ -66,12 +212,143 @@ type msrServiceImpl struct {
 	msrOffsets   []int64
 	fs           fileService
 	log          telegraf.Logger
+
+	// useMsrCPUFrequency enables the MSR-based frequency fallback for cores where
+	// scaling_cur_freq cannot be opened, seeked or read, e.g. isolated cores.
+	useMsrCPUFrequency bool
+	// tscFrequency is the TSC tick rate in MHz, derived once from MSR_PLATFORM_INFO/MSR_FSB_FREQ.
+	tscFrequency float64
+	// lastMsrFrequency holds the last MSR-derived frequency reported per core, used when the
+	// APERF/MPERF deltas haven't changed since the previous Gather cycle.
+	lastMsrFrequency map[string]float64
+	// isAtomFSBPlatform reports whether MSR_FSB_FREQ is meaningful on this CPU, see
+	// isAtomFSBPlatform (the function) and decodeFSBFrequencyMHz.
+	isAtomFSBPlatform bool
+
+	// readTimeout bounds every individual MSR read, see defaultMsrReadTimeout.
+	readTimeout time.Duration
+	// lastStaleWarning tracks, per core, the last time a timeout warning was logged for it.
+	lastStaleWarning map[string]time.Time
+
+	// msrFiles caches /dev/cpu/N/msr descriptors across Gather cycles, keyed by core.
+	msrFiles *fileCache
+	// cpuFreqFiles caches scaling_cur_freq descriptors across Gather cycles, keyed by core.
+	cpuFreqFiles *fileCache
+	// uncoreFreqFiles caches uncore frequency sysfs descriptors across Gather cycles, keyed by
+	// their (steady) path.
+	uncoreFreqFiles *fileCache
+
+	// vendor supplies the MSR offset table for the detected CPU vendor, see detectCPUVendor.
+	vendor cpuVendor
+
+	// outstandingTimedOutReadsMu guards outstandingTimedOutReads.
+	outstandingTimedOutReadsMu sync.Mutex
+	// outstandingTimedOutReads counts, per core, reads that have timed out but whose goroutine is
+	// still blocked in the underlying syscall, see maxOutstandingTimedOutReads. Scoped per core so
+	// a single permanently-wedged core can't exhaust the budget and mark healthy cores stale too.
+	outstandingTimedOutReads map[string]int32
+}
+
+// reserveTimedOutReadSlot reports whether another abandoned read may be started for core, claiming
+// a slot if so. Callers that get false must not spawn a new read for that core; it's already bad
+// enough that piling on more permanently-blocked goroutines would only make the leak worse. The
+// budget is tracked per core so one wedged core can't starve reads on every other core.
+func (m *msrServiceImpl) reserveTimedOutReadSlot(core string) bool {
+	m.outstandingTimedOutReadsMu.Lock()
+	defer m.outstandingTimedOutReadsMu.Unlock()
+
+	if m.outstandingTimedOutReads[core] >= maxOutstandingTimedOutReads {
+		return false
+	}
+	m.outstandingTimedOutReads[core]++
+	return true
+}
+
+func (m *msrServiceImpl) releaseTimedOutReadSlot(core string) {
+	m.outstandingTimedOutReadsMu.Lock()
+	defer m.outstandingTimedOutReadsMu.Unlock()
+
+	if m.outstandingTimedOutReads[core] <= 1 {
+		delete(m.outstandingTimedOutReads, core)
+		return
+	}
+	m.outstandingTimedOutReads[core]--
+}
+
+// fileCache lazily opens and keeps os.File descriptors open across Gather cycles, avoiding an
+// open/close pair per file per interval. Entries are invalidated on EBADF/ENXIO, which surface
+// when the underlying core or device has gone away (offline, hot-unplugged, etc.).
+type fileCache struct {
+	mu    sync.Mutex
+	files map[string]*os.File
+}
+
+func newFileCache() *fileCache {
+	return &fileCache{files: make(map[string]*os.File)}
+}
+
+// get returns the cached file for key, opening path if this is the first access.
+func (c *fileCache) get(key string, path string) (*os.File, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if f, ok := c.files[key]; ok {
+		return f, nil
+	}
+
+	if err := checkFile(path); err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("error opening file on path %q: %w", path, err)
+	}
+	c.files[key] = f
+	return f, nil
+}
+
+// invalidate closes and evicts the cached file for key, if any. The next get for key reopens it.
+func (c *fileCache) invalidate(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if f, ok := c.files[key]; ok {
+		f.Close()
+		delete(c.files, key)
+	}
+}
+
+// closeAll closes and evicts every cached file.
+func (c *fileCache) closeAll() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key, f := range c.files {
+		f.Close()
+		delete(c.files, key)
+	}
+}
+
+// isStaleFileCacheError reports whether err indicates that a cached descriptor's underlying
+// core or device has gone away and the cache entry should be invalidated.
+func isStaleFileCacheError(err error) bool {
+	return errors.Is(err, syscall.EBADF) || errors.Is(err, syscall.ENXIO)
 }
 
 func (m *msrServiceImpl) getCPUCoresData() map[string]*msrData {
 	return m.cpuCoresData
 }
 
+// Close releases every file descriptor cached by the service (MSR, scaling_cur_freq, and uncore
+// frequency files). It should be called from the plugin's Stop so Gather cycles don't leak fds
+// past shutdown.
+func (m *msrServiceImpl) Close() error {
+	m.msrFiles.closeAll()
+	m.cpuFreqFiles.closeAll()
+	m.uncoreFreqFiles.closeAll()
+	return nil
+}
+
 func (m *msrServiceImpl) isMsrLoaded() bool {
 	for cpuID := range m.getCPUCoresData() {
 		err := m.openAndReadMsr(cpuID)
@@ -83,18 +360,199 @@ func (m *msrServiceImpl) isMsrLoaded() bool {
 }
 func (m *msrServiceImpl) retrieveCPUFrequencyForCore(core string) (float64, error) {
 	cpuFreqPath := fmt.Sprintf(cpuCurrentFreqPartialPath, core)
-	err := checkFile(cpuFreqPath)
+	cpuFreqFile, err := m.cpuFreqFiles.get(core, cpuFreqPath)
 	if err != nil {
+		if m.useMsrCPUFrequency {
+			return m.retrieveCPUFrequencyFromMsr(core)
+		}
 		return 0, err
 	}
-	cpuFreqFile, err := os.Open(cpuFreqPath)
-	if err != nil {
-		return 0, fmt.Errorf("error opening scaling_cur_freq file on path %q: %w", cpuFreqPath, err)
+	if _, err := cpuFreqFile.Seek(0, io.SeekStart); err != nil {
+		m.cpuFreqFiles.invalidate(core)
+		if m.useMsrCPUFrequency {
+			return m.retrieveCPUFrequencyFromMsr(core)
+		}
+		return 0, fmt.Errorf("error seeking scaling_cur_freq file on path %q: %w", cpuFreqPath, err)
 	}
-	defer cpuFreqFile.Close()
 
 	cpuFreq, _, err := m.fs.readFileToFloat64(cpuFreqFile)
-	return convertKiloHertzToMegaHertz(cpuFreq), err
+	if err != nil {
+		if isStaleFileCacheError(err) {
+			m.cpuFreqFiles.invalidate(core)
+		}
+		if m.useMsrCPUFrequency {
+			return m.retrieveCPUFrequencyFromMsr(core)
+		}
+		return 0, err
+	}
+	return convertKiloHertzToMegaHertz(cpuFreq), nil
+}
+
+// retrieveCPUFrequencyFromMsr estimates the effective frequency of core directly from the
+// APERF/MPERF deltas already collected in readDataFromMsr, scaled by the TSC rate. It is used as
+// a fallback for cores where scaling_cur_freq cannot be opened, seeked or read, which typically
+// happens on isolated cores (e.g. via isolcpus or cpuset) where the cpufreq governor is
+// disabled. A readable but stuck scaling_cur_freq value does not trigger the fallback.
+func (m *msrServiceImpl) retrieveCPUFrequencyFromMsr(core string) (float64, error) {
+	if !m.vendor.supportsMsrCPUFrequency() {
+		// warnUnsupportedMetrics already warned about this once at startup; returning an error
+		// here on every Gather cycle would just turn into log spam for a vendor limitation that
+		// isn't going to change.
+		return 0, nil
+	}
+
+	data, ok := m.cpuCoresData[core]
+	if !ok {
+		return 0, fmt.Errorf("no MSR data collected for core %q", core)
+	}
+	if data.mperfDelta == 0 || data.aperfDelta == 0 {
+		// Nothing to compute this interval, e.g. the very first read. Report the previously
+		// estimated value instead of swinging to zero.
+		return m.lastMsrFrequency[core], nil
+	}
+
+	tscFreq, err := m.tscFrequencyMHz(core)
+	if err != nil {
+		return 0, fmt.Errorf("unable to determine TSC frequency for core %q: %w", core, err)
+	}
+
+	freq := clampCPUFrequencyMHz(tscFreq * float64(data.aperfDelta) / float64(data.mperfDelta))
+	m.lastMsrFrequency[core] = freq
+	return freq, nil
+}
+
+// tscFrequencyMHz returns the TSC tick rate in MHz, computed once from MSR_PLATFORM_INFO's
+// maximum non-turbo ratio multiplied by the bus clock. The bus clock is 100 MHz on modern Xeons.
+// MSR_FSB_FREQ only exists on the older Atom-family platforms listed in atomFamilyModels; reading
+// it anywhere else returns whatever garbage happens to be at that offset, which
+// decodeFSBFrequencyMHz would otherwise have no way to distinguish from a real encoding.
+func (m *msrServiceImpl) tscFrequencyMHz(core string) (float64, error) {
+	if m.tscFrequency != 0 {
+		return m.tscFrequency, nil
+	}
+
+	platformInfoValue, err := m.readSingleMsr(core, msrPlatformInfoString)
+	if err != nil {
+		return 0, err
+	}
+	// MSR_PLATFORM_INFO[15:8] - maximum non-turbo ratio.
+	maxNonTurboRatio := (platformInfoValue >> 8) & 0xFF
+	if maxNonTurboRatio == 0 {
+		return 0, fmt.Errorf("invalid maximum non-turbo ratio read from MSR_PLATFORM_INFO")
+	}
+
+	busClock := float64(busClockMHz)
+	if m.isAtomFSBPlatform {
+		// Don't fall back to busClockMHz on a read failure or a reserved encoding here: that
+		// default is only correct on modern Xeons, and m.tscFrequency below is cached for the
+		// life of the service, so silently using it on an Atom-family platform would lock in a
+		// wrong bus clock (and wrong reported frequency) even once MSR_FSB_FREQ becomes readable
+		// again next cycle.
+		fsbValue, err := m.readSingleMsr(core, msrFSBFreqString)
+		if err != nil {
+			return 0, err
+		}
+		decoded := decodeFSBFrequencyMHz(fsbValue)
+		if decoded == 0 {
+			return 0, fmt.Errorf("MSR_FSB_FREQ decoded to a reserved encoding")
+		}
+		busClock = decoded
+	}
+
+	m.tscFrequency = float64(maxNonTurboRatio) * busClock
+	return m.tscFrequency, nil
+}
+
+// decodeFSBFrequencyMHz decodes MSR_FSB_FREQ[2:0] into a bus clock in MHz, per the encoding used
+// on the Atom-family platforms in atomFamilyModels. Callers must only invoke this once
+// isAtomFSBPlatform has confirmed MSR_FSB_FREQ is backed by that encoding on this CPU; on every
+// other platform the bits at that offset mean something else (or nothing), so decoding them here
+// would produce a plausible-looking but wrong frequency. Returns 0 for reserved encodings.
+func decodeFSBFrequencyMHz(fsbFreqValue uint64) float64 {
+	switch fsbFreqValue & 0x7 {
+	case 0b101:
+		return 100
+	case 0b001:
+		return 133.33
+	case 0b011:
+		return 166.67
+	case 0b010:
+		return 200
+	case 0b000:
+		return 266.67
+	case 0b100:
+		return 333.33
+	case 0b110:
+		return 400
+	default:
+		return 0
+	}
+}
+
+// atomFamilyModels lists the family-6 model numbers (per /proc/cpuinfo's "model" field) of Intel
+// Atom-derived platforms (Bonnell through Airmont) known to back MSR_FSB_FREQ (0xCD) with the
+// encoding decodeFSBFrequencyMHz expects. Modern Xeon/Core platforms don't implement this MSR
+// meaningfully and use the fixed 100 MHz busClockMHz instead.
+var atomFamilyModels = map[int]bool{
+	0x1C: true, // Bonnell
+	0x26: true, // Bonnell
+	0x27: true, // Saltwell
+	0x35: true, // Saltwell
+	0x36: true, // Saltwell
+	0x37: true, // Silvermont
+	0x4A: true, // Silvermont
+	0x4D: true, // Silvermont
+	0x5A: true, // Silvermont
+	0x5D: true, // Silvermont
+	0x4C: true, // Airmont
+	0x75: true, // Airmont
+}
+
+// isAtomFSBPlatform reports whether cpuInfoPath describes an Intel Atom-family CPU (family 6,
+// model in atomFamilyModels), the only platforms where MSR_FSB_FREQ backs the bus-clock encoding
+// decodeFSBFrequencyMHz decodes. It degrades to false (i.e. always use busClockMHz) if cpuinfo
+// can't be read or parsed, same as detectCPUVendor's fallback.
+func isAtomFSBPlatform(cpuInfoPath string) bool {
+	data, err := os.ReadFile(cpuInfoPath)
+	if err != nil {
+		return false
+	}
+
+	var family, model int
+	haveFamily, haveModel := false, false
+	for _, line := range strings.Split(string(data), "\n") {
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		switch strings.TrimSpace(key) {
+		case "cpu family":
+			if n, err := strconv.Atoi(strings.TrimSpace(value)); err == nil {
+				family, haveFamily = n, true
+			}
+		case "model":
+			if n, err := strconv.Atoi(strings.TrimSpace(value)); err == nil {
+				model, haveModel = n, true
+			}
+		}
+		if haveFamily && haveModel {
+			break
+		}
+	}
+
+	return haveFamily && haveModel && family == 6 && atomFamilyModels[model]
+}
+
+// clampCPUFrequencyMHz bounds a MSR-derived frequency estimate to a plausible range, guarding
+// against implausible results caused by transient deltas or a misdetected TSC rate.
+func clampCPUFrequencyMHz(freq float64) float64 {
+	if freq < minPlausibleCPUFrequencyMHz {
+		return minPlausibleCPUFrequencyMHz
+	}
+	if freq > maxPlausibleCPUFrequencyMHz {
+		return maxPlausibleCPUFrequencyMHz
+	}
+	return freq
 }
 
 func (m *msrServiceImpl) retrieveUncoreFrequency(socketID string, typeFreq string, kind string, die string) (float64, error) {
@@ -102,18 +560,26 @@ func (m *msrServiceImpl) retrieveUncoreFrequency(socketID string, typeFreq strin
 	if err != nil {
 		return 0, fmt.Errorf("unable to create uncore freq read path for socketID %q, and frequency type %q: %w", socketID, typeFreq, err)
 	}
-	err = checkFile(uncoreFreqPath)
+
+	// The uncore frequency path never changes for a given socket/die/kind, so it doubles as the
+	// cache key.
+	uncoreFreqFile, err := m.uncoreFreqFiles.get(uncoreFreqPath, uncoreFreqPath)
 	if err != nil {
 		return 0, err
 	}
-	uncoreFreqFile, err := os.Open(uncoreFreqPath)
-	if err != nil {
-		return 0, fmt.Errorf("error opening uncore frequncy file on %q: %w", uncoreFreqPath, err)
+	if _, err := uncoreFreqFile.Seek(0, io.SeekStart); err != nil {
+		m.uncoreFreqFiles.invalidate(uncoreFreqPath)
+		return 0, fmt.Errorf("error seeking uncore frequncy file on %q: %w", uncoreFreqPath, err)
 	}
-	defer uncoreFreqFile.Close()
 
 	uncoreFreq, _, err := m.fs.readFileToFloat64(uncoreFreqFile)
-	return convertKiloHertzToMegaHertz(uncoreFreq), err
+	if err != nil {
+		if isStaleFileCacheError(err) {
+			m.uncoreFreqFiles.invalidate(uncoreFreqPath)
+		}
+		return 0, err
+	}
+	return convertKiloHertzToMegaHertz(uncoreFreq), nil
 }
 
 func createUncoreFreqPath(socketID string, typeFreq string, kind string, die string) (string, error) {
@@ -142,18 +608,16 @@ func createUncoreFreqPath(socketID string, typeFreq string, kind string, die str
 
 func (m *msrServiceImpl) openAndReadMsr(core string) error {
 	path := fmt.Sprintf(msrPartialPath, core)
-	err := checkFile(path)
+	msrFile, err := m.msrFiles.get(core, path)
 	if err != nil {
 		return err
 	}
-	msrFile, err := os.Open(path)
-	if err != nil {
-		return fmt.Errorf("error opening MSR file on path %q: %w", path, err)
-	}
-	defer msrFile.Close()
 
 	err = m.readDataFromMsr(core, msrFile)
 	if err != nil {
+		if isStaleFileCacheError(err) {
+			m.msrFiles.invalidate(core)
+		}
 		return fmt.Errorf("error reading data from MSR for core %q: %w", core, err)
 	}
 	return nil
@@ -161,15 +625,10 @@ func (m *msrServiceImpl) openAndReadMsr(core string) error {
 
 func (m *msrServiceImpl) readSingleMsr(core string, msr string) (uint64, error) {
 	path := fmt.Sprintf(msrPartialPath, core)
-	err := checkFile(path)
+	msrFile, err := m.msrFiles.get(core, path)
 	if err != nil {
 		return 0, err
 	}
-	msrFile, err := os.Open(path)
-	if err != nil {
-		return 0, fmt.Errorf("error opening MSR file on path %q: %w", path, err)
-	}
-	defer msrFile.Close()
 
 	var msrAddress int64
 	switch msr {
@@ -191,88 +650,169 @@ func (m *msrServiceImpl) readSingleMsr(core string, msr string) (uint64, error)
 		return 0, fmt.Errorf("incorect name of MSR %s", msr)
 	}
 
-	value, err := m.fs.readFileAtOffsetToUint64(msrFile, msrAddress)
+	value, err := m.readValueWithTimeout(core, msrFile, msrAddress)
 	if err != nil {
+		if isStaleFileCacheError(err) {
+			m.msrFiles.invalidate(core)
+		}
 		return 0, err
 	}
 
 	return value, nil
 }
 
-func (m *msrServiceImpl) readDataFromMsr(core string, reader io.ReaderAt) error {
-	g, ctx := errgroup.WithContext(context.Background())
+// readValueWithTimeout reads a single MSR offset, bounding the read by m.readTimeout so a stuck
+// pread on /dev/cpu/N/msr cannot hang the caller. The read itself runs in its own goroutine,
+// since a blocked syscall cannot be interrupted directly; on timeout that goroutine is leaked
+// until the read eventually returns, but the caller is freed to continue gathering other cores.
+//
+// Deprecated: this wraps the single-offset fs.readFileAtOffsetToUint64; readDataFromMsr now
+// uses the batched fs.readFileAtOffsetsToUint64 path instead. Kept for readSingleMsr and tests.
+func (m *msrServiceImpl) readValueWithTimeout(core string, reader io.ReaderAt, offset int64) (uint64, error) {
+	if !m.reserveTimedOutReadSlot(core) {
+		return 0, fmt.Errorf("%w, refusing to start another read of %s for core %q", errTooManyTimedOutReads, msrOffsetName(offset), core)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), m.readTimeout)
+	defer cancel()
+
+	result := make(chan uint64, 1)
+	errs := make(chan error, 1)
+	go func() {
+		value, err := m.fs.readFileAtOffsetToUint64(reader, offset)
+		if err != nil {
+			errs <- err
+			return
+		}
+		result <- value
+	}()
 
-	// Create and populate a map that contains msr offsets along with their respective channels
-	msrOffsetsWithChannels := make(map[int64]chan uint64)
-	for _, offset := range m.msrOffsets {
-		msrOffsetsWithChannels[offset] = make(chan uint64)
+	select {
+	case value := <-result:
+		m.releaseTimedOutReadSlot(core)
+		return value, nil
+	case err := <-errs:
+		m.releaseTimedOutReadSlot(core)
+		return 0, err
+	case <-ctx.Done():
+		// The goroutine above is still blocked in the pread; release its slot once it finally
+		// returns instead of here, so the cap reflects reads that are genuinely still stuck.
+		go func() {
+			select {
+			case <-result:
+			case <-errs:
+			}
+			m.releaseTimedOutReadSlot(core)
+		}()
+		return 0, fmt.Errorf("timed out after %s reading MSR %s: %w", m.readTimeout, msrOffsetName(offset), ctx.Err())
 	}
+}
 
-	// Start a goroutine for each msr offset
-	for offset, channel := range msrOffsetsWithChannels {
-		// Wrap around function to avoid race on loop counter
-		func(off int64, ch chan uint64) {
-			g.Go(func() error {
-				defer close(ch)
-
-				err := m.readValueFromFileAtOffset(ctx, ch, reader, off)
-				if err != nil {
-					return fmt.Errorf("error reading MSR file: %w", err)
-				}
-
-				return nil
-			})
-		}(offset, channel)
-	}
-
-	newC3 := <-msrOffsetsWithChannels[c3StateResidencyLocation]
-	newC6 := <-msrOffsetsWithChannels[c6StateResidencyLocation]
-	newC7 := <-msrOffsetsWithChannels[c7StateResidencyLocation]
-	newMperf := <-msrOffsetsWithChannels[maximumFrequencyClockCountLocation]
-	newAperf := <-msrOffsetsWithChannels[actualFrequencyClockCountLocation]
-	newTsc := <-msrOffsetsWithChannels[timestampCounterLocation]
-	newThrottleTemp := <-msrOffsetsWithChannels[throttleTemperatureLocation]
-	newTemp := <-msrOffsetsWithChannels[temperatureLocation]
-
-	if err := g.Wait(); err != nil {
-		return fmt.Errorf("received error during reading MSR values in goroutines: %w", err)
-	}
-
-	m.cpuCoresData[core].c3Delta = newC3 - m.cpuCoresData[core].c3
-	m.cpuCoresData[core].c6Delta = newC6 - m.cpuCoresData[core].c6
-	m.cpuCoresData[core].c7Delta = newC7 - m.cpuCoresData[core].c7
-	m.cpuCoresData[core].mperfDelta = newMperf - m.cpuCoresData[core].mperf
-	m.cpuCoresData[core].aperfDelta = newAperf - m.cpuCoresData[core].aperf
-	m.cpuCoresData[core].timeStampCounterDelta = newTsc - m.cpuCoresData[core].timeStampCounter
-
-	m.cpuCoresData[core].c3 = newC3
-	m.cpuCoresData[core].c6 = newC6
-	m.cpuCoresData[core].c7 = newC7
-	m.cpuCoresData[core].mperf = newMperf
-	m.cpuCoresData[core].aperf = newAperf
-	m.cpuCoresData[core].timeStampCounter = newTsc
-	// MSR (1A2h) IA32_TEMPERATURE_TARGET bits 23:16.
-	m.cpuCoresData[core].throttleTemp = int64((newThrottleTemp >> 16) & 0xFF)
-	// MSR (19Ch) IA32_THERM_STATUS bits 22:16.
-	m.cpuCoresData[core].temp = int64((newTemp >> 16) & 0x7F)
+// readDataFromMsr reads every offset in m.msrOffsets with a single batched call and assigns the
+// results to the matching cpuCoresData fields by index. fs.readFileAtOffsetsToUint64 returns
+// values in the order of the offsets it is given, so values[i] is always the value read at
+// m.msrOffsets[i].
+func (m *msrServiceImpl) readDataFromMsr(core string, reader io.ReaderAt) error {
+	values, err := m.readOffsetsWithTimeout(core, reader, m.msrOffsets)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, errTooManyTimedOutReads) {
+			// Leave the previously stored values untouched so deltas don't go wildly negative
+			// on the next cycle once the read succeeds again.
+			m.warnStaleCore(core)
+			return nil
+		}
+		return fmt.Errorf("error reading MSR file: %w", err)
+	}
+
+	data := m.cpuCoresData[core]
+	for i, offset := range m.msrOffsets {
+		value := values[i]
+		switch offset {
+		case c3StateResidencyLocation:
+			data.c3Delta = value - data.c3
+			data.c3 = value
+		case c6StateResidencyLocation:
+			data.c6Delta = value - data.c6
+			data.c6 = value
+		case c7StateResidencyLocation:
+			data.c7Delta = value - data.c7
+			data.c7 = value
+		case maximumFrequencyClockCountLocation:
+			data.mperfDelta = value - data.mperf
+			data.mperf = value
+		case actualFrequencyClockCountLocation:
+			data.aperfDelta = value - data.aperf
+			data.aperf = value
+		case timestampCounterLocation:
+			data.timeStampCounterDelta = value - data.timeStampCounter
+			data.timeStampCounter = value
+		case throttleTemperatureLocation:
+			// MSR (1A2h) IA32_TEMPERATURE_TARGET bits 23:16.
+			data.throttleTemp = int64((value >> 16) & 0xFF)
+		case temperatureLocation:
+			// MSR (19Ch) IA32_THERM_STATUS bits 22:16.
+			data.temp = int64((value >> 16) & 0x7F)
+		}
+	}
 
 	return nil
 }
 
-func (m *msrServiceImpl) readValueFromFileAtOffset(ctx context.Context, ch chan uint64, reader io.ReaderAt, offset int64) error {
-	value, err := m.fs.readFileAtOffsetToUint64(reader, offset)
-	if err != nil {
-		return err
+// readOffsetsWithTimeout reads every offset in a single call to fs.readFileAtOffsetsToUint64,
+// bounding the whole batch by m.readTimeout. The read runs in its own goroutine, since a blocked
+// syscall cannot be interrupted directly; on timeout that goroutine is leaked until the
+// underlying reads eventually return, but the caller is freed to continue gathering other cores.
+func (m *msrServiceImpl) readOffsetsWithTimeout(core string, reader io.ReaderAt, offsets []int64) ([]uint64, error) {
+	if !m.reserveTimedOutReadSlot(core) {
+		return nil, fmt.Errorf("%w, refusing to start another batched read for core %q", errTooManyTimedOutReads, core)
 	}
 
-	// Detect context cancellation and return an error if other goroutine fails
+	ctx, cancel := context.WithTimeout(context.Background(), m.readTimeout)
+	defer cancel()
+
+	type batchResult struct {
+		values []uint64
+		err    error
+	}
+	done := make(chan batchResult, 1)
+	go func() {
+		values, err := m.fs.readFileAtOffsetsToUint64(reader, offsets)
+		done <- batchResult{values, err}
+	}()
+
 	select {
+	case res := <-done:
+		m.releaseTimedOutReadSlot(core)
+		return res.values, res.err
 	case <-ctx.Done():
-		return ctx.Err()
-	case ch <- value:
+		// The goroutine above is still blocked reading offsets; release its slot once it
+		// finally returns instead of here, so the cap reflects reads that are genuinely stuck.
+		go func() {
+			<-done
+			m.releaseTimedOutReadSlot(core)
+		}()
+		return nil, ctx.Err()
 	}
+}
 
-	return nil
+// warnStaleCore logs, at most once per staleCoreWarnInterval per core, that a core's MSR data
+// is being treated as stale for this interval because the batched read timed out.
+func (m *msrServiceImpl) warnStaleCore(core string) {
+	now := time.Now()
+	if last, ok := m.lastStaleWarning[core]; ok && now.Sub(last) < staleCoreWarnInterval {
+		return
+	}
+	m.lastStaleWarning[core] = now
+
+	// The batched read collapses per-offset attribution, so we can't name the single offset that
+	// actually stalled the pread; list everything that was being read instead, so an operator
+	// investigating a stuck core at least knows which MSRs are in play.
+	names := make([]string, len(m.msrOffsets))
+	for i, offset := range m.msrOffsets {
+		names[i] = msrOffsetName(offset)
+	}
+	m.log.Warnf("timed out reading MSR data for core %q after %s, skipping this interval (offsets: %s)",
+		core, m.readTimeout, strings.Join(names, ", "))
 }
 
 // setCPUCores initialize cpuCoresData map.
@@ -319,12 +859,26 @@ func (m *msrServiceImpl) setCPUCores() error {
 	return nil
 }
 
-func newMsrServiceWithFs(logger telegraf.Logger, fs fileService, cores []string) *msrServiceImpl {
+func newMsrServiceWithFs(logger telegraf.Logger, fs fileService, cores []string, useMsrCPUFrequency bool, readTimeout time.Duration) *msrServiceImpl {
+	if readTimeout <= 0 {
+		readTimeout = defaultMsrReadTimeout
+	}
 	parsedCores := parseCores(logger, cores)
+	vendor := detectCPUVendor(logger, cpuInfoPath)
 	msrService := &msrServiceImpl{
-		fs:       fs,
-		log:      logger,
-		cpuCores: parsedCores,
+		fs:                       fs,
+		log:                      logger,
+		cpuCores:                 parsedCores,
+		useMsrCPUFrequency:       useMsrCPUFrequency,
+		lastMsrFrequency:         make(map[string]float64),
+		isAtomFSBPlatform:        isAtomFSBPlatform(cpuInfoPath),
+		readTimeout:              readTimeout,
+		lastStaleWarning:         make(map[string]time.Time),
+		msrFiles:                 newFileCache(),
+		cpuFreqFiles:             newFileCache(),
+		uncoreFreqFiles:          newFileCache(),
+		vendor:                   vendor,
+		outstandingTimedOutReads: make(map[string]int32),
 	}
 	err := msrService.setCPUCores()
 	if err != nil {
@@ -332,12 +886,27 @@ func newMsrServiceWithFs(logger telegraf.Logger, fs fileService, cores []string)
 		msrService.log.Error(err)
 	}
 
-	msrService.msrOffsets = []int64{c3StateResidencyLocation, c6StateResidencyLocation, c7StateResidencyLocation,
-		maximumFrequencyClockCountLocation, actualFrequencyClockCountLocation, timestampCounterLocation,
-		throttleTemperatureLocation, temperatureLocation}
+	msrService.msrOffsets = vendor.msrOffsets()
+	// Sorted so the batched read in readDataFromMsr walks the MSR file in ascending offset order.
+	sort.Slice(msrService.msrOffsets, func(i, j int) bool { return msrService.msrOffsets[i] < msrService.msrOffsets[j] })
+	msrService.warnUnsupportedMetrics()
 	return msrService
 }
 
+// warnUnsupportedMetrics logs, once at startup, which metrics this vendor's MSR layout can't
+// provide, so the plugin degrades visibly instead of silently omitting or misdecoding them.
+func (m *msrServiceImpl) warnUnsupportedMetrics() {
+	if !m.vendor.supportsCState() {
+		m.log.Warnf("%s CPUs do not expose C-state residency through the MSRs this plugin reads; C3/C6/C7 metrics will not be reported", m.vendor.vendorName())
+	}
+	if !m.vendor.supportsTemperature() {
+		m.log.Warnf("%s CPUs do not expose core temperature through IA32_THERM_STATUS; temperature and throttle temperature metrics will not be reported", m.vendor.vendorName())
+	}
+	if m.useMsrCPUFrequency && !m.vendor.supportsMsrCPUFrequency() {
+		m.log.Warnf("use_msr_cpu_frequency is enabled, but %s CPUs are not supported by the MSR-based frequency fallback; affected cores will report a frequency of 0 when scaling_cur_freq is unavailable", m.vendor.vendorName())
+	}
+}
+
 func parseCores(logger telegraf.Logger, cores []string) []int {
 	if cores == nil {
 		logger.Debug("all possible cores will be configured")
@@ -0,0 +1,272 @@
+//go:build linux
+
+package intel_powerstat
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"testing"
+	"time"
+)
+
+// discardLogger is a minimal telegraf.Logger that throws every message away. It lets tests
+// exercise logging paths without a real logger.
+type discardLogger struct{}
+
+func (discardLogger) Level() int                         { return 0 }
+func (discardLogger) AddAttribute(key string, value any) {}
+func (discardLogger) Error(args ...any)                  {}
+func (discardLogger) Errorf(format string, args ...any)  {}
+func (discardLogger) Debug(args ...any)                  {}
+func (discardLogger) Debugf(format string, args ...any)  {}
+func (discardLogger) Warn(args ...any)                   {}
+func (discardLogger) Warnf(format string, args ...any)   {}
+func (discardLogger) Info(args ...any)                   {}
+func (discardLogger) Infof(format string, args ...any)   {}
+
+func TestDetectCPUVendorIntel(t *testing.T) {
+	path := writeCPUInfo(t, "vendor_id\t: GenuineIntel\n")
+
+	vendor := detectCPUVendor(discardLogger{}, path)
+	if _, ok := vendor.(intelCPUVendor); !ok {
+		t.Fatalf("expected intelCPUVendor, got %T", vendor)
+	}
+}
+
+func TestDetectCPUVendorAMD(t *testing.T) {
+	path := writeCPUInfo(t, "vendor_id\t: AuthenticAMD\n")
+
+	vendor := detectCPUVendor(discardLogger{}, path)
+	if _, ok := vendor.(amdCPUVendor); !ok {
+		t.Fatalf("expected amdCPUVendor, got %T", vendor)
+	}
+}
+
+func TestDetectCPUVendorUnknownFallsBackToIntel(t *testing.T) {
+	path := writeCPUInfo(t, "vendor_id\t: SomeFutureVendor\n")
+
+	vendor := detectCPUVendor(discardLogger{}, path)
+	if _, ok := vendor.(intelCPUVendor); !ok {
+		t.Fatalf("expected fallback to intelCPUVendor, got %T", vendor)
+	}
+}
+
+func TestDetectCPUVendorMissingFileFallsBackToIntel(t *testing.T) {
+	vendor := detectCPUVendor(discardLogger{}, t.TempDir()+"/does-not-exist")
+	if _, ok := vendor.(intelCPUVendor); !ok {
+		t.Fatalf("expected fallback to intelCPUVendor, got %T", vendor)
+	}
+}
+
+func writeCPUInfo(t *testing.T, contents string) string {
+	t.Helper()
+	path := t.TempDir() + "/cpuinfo"
+	if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+		t.Fatalf("writing fake cpuinfo: %v", err)
+	}
+	return path
+}
+
+// blockingReaderAt blocks every ReadAt until released is closed. It simulates a
+// core wedged mid-pread, e.g. under an SMI storm, so readValueWithTimeout/readOffsetsWithTimeout
+// can be exercised without an actual stuck /dev/cpu/N/msr.
+type blockingReaderAt struct {
+	released chan struct{}
+}
+
+func newBlockingReaderAt() *blockingReaderAt {
+	return &blockingReaderAt{released: make(chan struct{})}
+}
+
+func (r *blockingReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	<-r.released
+	return len(p), nil
+}
+
+var _ io.ReaderAt = (*blockingReaderAt)(nil)
+
+func newTestMsrService() *msrServiceImpl {
+	return &msrServiceImpl{
+		fs:                       newFileService(),
+		log:                      discardLogger{},
+		msrOffsets:               intelCPUVendor{}.msrOffsets(),
+		readTimeout:              10 * time.Millisecond,
+		lastStaleWarning:         make(map[string]time.Time),
+		outstandingTimedOutReads: make(map[string]int32),
+	}
+}
+
+func TestReadValueWithTimeoutCancelsOnBlockedRead(t *testing.T) {
+	m := newTestMsrService()
+	reader := newBlockingReaderAt()
+	defer close(reader.released)
+
+	_, err := m.readValueWithTimeout("0", reader, timestampCounterLocation)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected a timeout error wrapping context.DeadlineExceeded, got: %v", err)
+	}
+}
+
+func TestReadOffsetsWithTimeoutCancelsOnBlockedRead(t *testing.T) {
+	m := newTestMsrService()
+	reader := newBlockingReaderAt()
+	defer close(reader.released)
+
+	_, err := m.readOffsetsWithTimeout("0", reader, m.msrOffsets)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected a timeout error wrapping context.DeadlineExceeded, got: %v", err)
+	}
+}
+
+func TestReadOffsetsWithTimeoutRefusesAfterCap(t *testing.T) {
+	m := newTestMsrService()
+	reader := newBlockingReaderAt()
+	defer close(reader.released)
+
+	for i := int32(0); i < maxOutstandingTimedOutReads; i++ {
+		if _, err := m.readOffsetsWithTimeout("0", reader, m.msrOffsets); !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("read %d: expected a timeout error, got: %v", i, err)
+		}
+	}
+
+	if _, err := m.readOffsetsWithTimeout("0", reader, m.msrOffsets); !errors.Is(err, errTooManyTimedOutReads) {
+		t.Fatalf("expected errTooManyTimedOutReads for core \"0\" once its cap is reached, got: %v", err)
+	}
+}
+
+// TestReadOffsetsWithTimeoutCapIsPerCore is the regression test for the shared-counter bug: a
+// single core pinned at the outstanding-read cap must not cause errTooManyTimedOutReads on a
+// different, healthy core. Each core gets its own budget.
+func TestReadOffsetsWithTimeoutCapIsPerCore(t *testing.T) {
+	m := newTestMsrService()
+	wedgedReader := newBlockingReaderAt()
+	defer close(wedgedReader.released)
+
+	for i := int32(0); i < maxOutstandingTimedOutReads; i++ {
+		if _, err := m.readOffsetsWithTimeout("0", wedgedReader, m.msrOffsets); !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("read %d on core \"0\": expected a timeout error, got: %v", i, err)
+		}
+	}
+	if _, err := m.readOffsetsWithTimeout("0", wedgedReader, m.msrOffsets); !errors.Is(err, errTooManyTimedOutReads) {
+		t.Fatalf("expected core \"0\" to be capped, got: %v", err)
+	}
+
+	healthyReader := newFakeMsrReaderAt()
+	if _, err := m.readOffsetsWithTimeout("1", healthyReader, m.msrOffsets); err != nil {
+		t.Fatalf("core \"1\" should be unaffected by core \"0\" being capped, got: %v", err)
+	}
+}
+
+func TestReadDataFromMsrSucceedsWithinTimeout(t *testing.T) {
+	m := newTestMsrService()
+	m.cpuCoresData = map[string]*msrData{"0": {}}
+
+	if err := m.readDataFromMsr("0", newFakeMsrReaderAt()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+// fakeMsrReaderAt returns zeroed data for any offset, fast enough to stay under readTimeout.
+type fakeMsrReaderAt struct{}
+
+func newFakeMsrReaderAt() *fakeMsrReaderAt {
+	return &fakeMsrReaderAt{}
+}
+
+func (r *fakeMsrReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	return len(p), nil
+}
+
+var _ io.ReaderAt = (*fakeMsrReaderAt)(nil)
+
+func TestRetrieveCPUFrequencyFromMsrUnsupportedVendorShortCircuits(t *testing.T) {
+	m := newTestMsrService()
+	m.vendor = amdCPUVendor{}
+	m.cpuCoresData = map[string]*msrData{"0": {mperfDelta: 1, aperfDelta: 1}}
+
+	freq, err := m.retrieveCPUFrequencyFromMsr("0")
+	if err != nil {
+		t.Fatalf("expected no error for an unsupported vendor, got: %v", err)
+	}
+	if freq != 0 {
+		t.Fatalf("expected a zero frequency for an unsupported vendor, got: %v", freq)
+	}
+}
+
+func TestIsAtomFSBPlatform(t *testing.T) {
+	tests := []struct {
+		name     string
+		cpuinfo  string
+		expected bool
+	}{
+		{"known atom family/model", "cpu family\t: 6\nmodel\t\t: 55\n", true},
+		{"non-atom model, same family", "cpu family\t: 6\nmodel\t\t: 158\n", false},
+		{"non-intel family", "cpu family\t: 23\nmodel\t\t: 55\n", false},
+		{"missing fields", "vendor_id\t: GenuineIntel\n", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeCPUInfo(t, tt.cpuinfo)
+			if got := isAtomFSBPlatform(path); got != tt.expected {
+				t.Fatalf("isAtomFSBPlatform(%q) = %v, want %v", tt.cpuinfo, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestIsAtomFSBPlatformMissingFile(t *testing.T) {
+	if isAtomFSBPlatform(t.TempDir() + "/does-not-exist") {
+		t.Fatal("expected false when cpuinfo can't be read")
+	}
+}
+
+// manyCoreReaderAt simulates a single core's /dev/cpu/N/msr: every offset read succeeds
+// immediately, used to benchmark Gather latency across a large core count.
+type manyCoreReaderAt struct{}
+
+func (manyCoreReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	return len(p), nil
+}
+
+// BenchmarkReadDataFromMsrBatched measures the batched fs.readFileAtOffsetsToUint64 path across
+// a 64-core system, one Gather cycle per core.
+func BenchmarkReadDataFromMsrBatched(b *testing.B) {
+	m := newTestMsrService()
+	reader := manyCoreReaderAt{}
+	const cores = 64
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for c := 0; c < cores; c++ {
+			core := fmt.Sprintf("%d", c)
+			m.cpuCoresData = map[string]*msrData{core: {}}
+			if err := m.readDataFromMsr(core, reader); err != nil {
+				b.Fatalf("unexpected error: %v", err)
+			}
+		}
+	}
+}
+
+// BenchmarkReadDataFromMsrPerOffset measures the earlier approach of issuing one
+// readValueWithTimeout call (its own goroutine and timeout) per offset, for comparison against
+// BenchmarkReadDataFromMsrBatched across the same 64-core system.
+func BenchmarkReadDataFromMsrPerOffset(b *testing.B) {
+	m := newTestMsrService()
+	reader := manyCoreReaderAt{}
+	const cores = 64
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for c := 0; c < cores; c++ {
+			core := fmt.Sprintf("%d", c)
+			for _, offset := range m.msrOffsets {
+				if _, err := m.readValueWithTimeout(core, reader, offset); err != nil {
+					b.Fatalf("unexpected error: %v", err)
+				}
+			}
+		}
+	}
+}